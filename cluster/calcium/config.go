@@ -0,0 +1,17 @@
+package calcium
+
+import "github.com/projecteru2/core/config"
+
+// OnConfigChange adopts a hot-reloaded config as c's effective one. Wire it
+// up with manager.OnChange(c.OnConfigChange) wherever the Calcium and its
+// config.Manager are constructed together; the manager then calls it
+// synchronously right after a successful reload.
+//
+// c.config is an *atomic.Pointer[config.Config]: reload runs on the
+// manager's own goroutine while in-flight operations (e.g.
+// snapshotNodeWorkloads' c.config.Load().LockTimeout()) read it
+// concurrently from theirs, so the swap itself has to be atomic, not just
+// the assignment of a struct field under the Go memory model.
+func (c *Calcium) OnConfigChange(_, cfg *config.Config) {
+	c.config.Store(cfg)
+}
@@ -0,0 +1,22 @@
+package calcium
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/core/config"
+	"github.com/projecteru2/core/types"
+)
+
+func TestOnConfigChangeSwapsConfig(t *testing.T) {
+	c := NewTestCluster()
+	old := c.config.Load()
+
+	newCfg := &config.Config{Scheduler: types.SchedConfig{LockTTL: 42}}
+	c.OnConfigChange(old, newCfg)
+
+	assert.Same(t, newCfg, c.config.Load())
+	assert.Equal(t, 42*time.Second, c.config.Load().LockTimeout())
+}
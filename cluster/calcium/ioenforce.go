@@ -0,0 +1,231 @@
+package calcium
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+
+	"github.com/projecteru2/core/types"
+)
+
+// cgroup v2 exposes a single io.max file; v1 splits the same limits across
+// four blkio.throttle.* files, one per direction and unit.
+const (
+	cgroupV2IOMax     = "io.max"
+	cgroupV1ReadBPS   = "blkio.throttle.read_bps_device"
+	cgroupV1WriteBPS  = "blkio.throttle.write_bps_device"
+	cgroupV1ReadIOPS  = "blkio.throttle.read_iops_device"
+	cgroupV1WriteIOPS = "blkio.throttle.write_iops_device"
+)
+
+// cgroupPathForWorkload resolves the cgroup directory enforcing a
+// workload's block IO limits. It's a package var, not a constant lookup,
+// so tests can substitute a fake directory instead of depending on a real
+// container's cgroup existing on the test host.
+var cgroupPathForWorkload = func(workload *types.Workload) string {
+	return filepath.Join("/sys/fs/cgroup/system.slice", fmt.Sprintf("docker-%s.scope", workload.ID))
+}
+
+// resolveBlockDevice resolves a bind mount source to the major:minor device
+// number backing it. A package var for the same reason as
+// cgroupPathForWorkload: tests shouldn't need a real mounted filesystem.
+var resolveBlockDevice = blockDeviceOf
+
+// enforceVolumeIOLimits writes io.max (or the cgroup v1 blkio.throttle.*
+// equivalents when v2 isn't mounted) for every binding with non-zero IO
+// parameters, so the limits VolumeBinding already parses are actually
+// applied to the workload's block devices instead of only being recorded.
+//
+// This is invoked from doRemapResourceAndLog, parallel to
+// VirtualizationResourceRemap: both reconcile a workload's resources
+// against its current bindings on every remap pass.
+func (c *Calcium) enforceVolumeIOLimits(cgroupPath string, bindings types.VolumeBindings) error {
+	v2Path := filepath.Join(cgroupPath, cgroupV2IOMax)
+	useV2 := fileExists(v2Path)
+
+	for _, vb := range bindings {
+		if vb.ReadIOPS == 0 && vb.WriteIOPS == 0 && vb.ReadBytes == 0 && vb.WriteBytes == 0 {
+			continue
+		}
+		maj, min, err := resolveBlockDevice(vb.Source)
+		if err != nil {
+			return err
+		}
+
+		if useV2 {
+			if err := appendCgroupFile(v2Path, ioMaxLine(maj, min, vb)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeCgroupV1Limits(cgroupPath, maj, min, vb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// volumeIODiffs compares each of a workload's VolumeBinding IO limits
+// against what's currently written to its cgroup, returning one line per
+// binding whose device has an io.max line with different values - e.g. the
+// binding's limits changed since enforceVolumeIOLimits last wrote them. A
+// device with no io.max line yet, or a cgroup without io.max, yields no diff.
+func (c *Calcium) volumeIODiffs(workload *types.Workload) (diffs []string) {
+	cgroupPath := cgroupPathForWorkload(workload)
+	for _, vb := range workload.VolumeBindings {
+		if vb.ReadIOPS == 0 && vb.WriteIOPS == 0 && vb.ReadBytes == 0 && vb.WriteBytes == 0 {
+			continue
+		}
+		maj, min, err := resolveBlockDevice(vb.Source)
+		if err != nil {
+			diffs = append(diffs, fmt.Sprintf("%s: failed to resolve device for volume %s: %s", workload.ID, vb.Source, err))
+			continue
+		}
+
+		want := ioMaxLine(maj, min, vb)
+		got, err := currentIOMaxLine(cgroupPath, maj, min)
+		if err != nil {
+			diffs = append(diffs, fmt.Sprintf("%s: failed to read current IO limits for volume %s: %s", workload.ID, vb.Source, err))
+			continue
+		}
+		if got != "" && got != want {
+			diffs = append(diffs, fmt.Sprintf("%s: volume IO limit drift on %s: want %q, have %q", workload.ID, vb.Source, want, got))
+		}
+	}
+	return diffs
+}
+
+// currentIOMaxLine returns the io.max line currently in effect for a
+// device, or "" if cgroup v2 isn't mounted or the device has no line yet.
+func currentIOMaxLine(cgroupPath string, maj, min uint32) (string, error) {
+	v2Path := filepath.Join(cgroupPath, cgroupV2IOMax)
+	if !fileExists(v2Path) {
+		return "", nil
+	}
+
+	f, err := os.Open(v2Path)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer f.Close()
+
+	prefix := fmt.Sprintf("%d:%d ", maj, min)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, prefix) {
+			return line, nil
+		}
+	}
+	return "", errors.WithStack(scanner.Err())
+}
+
+// ioMaxLine renders the cgroup v2 io.max line for a single VolumeBinding,
+// e.g. "253:0 rbps=1048576 wbps=max riops=max wiops=max".
+func ioMaxLine(maj, min uint32, vb *types.VolumeBinding) string {
+	field := func(v int64) string {
+		if v == 0 {
+			return "max"
+		}
+		return fmt.Sprintf("%d", v)
+	}
+	return fmt.Sprintf("%d:%d rbps=%s wbps=%s riops=%s wiops=%s",
+		maj, min, field(vb.ReadBytes), field(vb.WriteBytes), field(vb.ReadIOPS), field(vb.WriteIOPS))
+}
+
+func writeCgroupV1Limits(cgroupPath string, maj, min uint32, vb *types.VolumeBinding) error {
+	device := fmt.Sprintf("%d:%d", maj, min)
+	limits := []struct {
+		file  string
+		limit int64
+	}{
+		{cgroupV1ReadBPS, vb.ReadBytes},
+		{cgroupV1WriteBPS, vb.WriteBytes},
+		{cgroupV1ReadIOPS, vb.ReadIOPS},
+		{cgroupV1WriteIOPS, vb.WriteIOPS},
+	}
+	for _, l := range limits {
+		if l.limit == 0 {
+			continue
+		}
+		if err := appendCgroupFile(filepath.Join(cgroupPath, l.file), fmt.Sprintf("%s %d", device, l.limit)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockDeviceOf resolves the major:minor device number backing a bind
+// mount's source, by finding the mount point covering it in
+// /proc/self/mountinfo and stat-ing that mount point rather than source
+// itself, since source may be a subdirectory of a larger mounted filesystem.
+func blockDeviceOf(source string) (maj, min uint32, err error) {
+	mountpoint, err := findMountpoint(source)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var st syscall.Stat_t
+	if err := syscall.Stat(mountpoint, &st); err != nil {
+		return 0, 0, errors.WithStack(err)
+	}
+	// st.Dev packs major:minor per the glibc/kernel dev_t layout, which
+	// differs from the naive (dev>>8, dev&0xff) split once minor numbers
+	// need more than 8 bits; unix.Major/Minor decode it correctly.
+	dev := uint64(st.Dev) //nolint:unconvert // st.Dev's width varies by platform
+	return unix.Major(dev), unix.Minor(dev), nil
+}
+
+// findMountpoint walks /proc/self/mountinfo and returns the longest mount
+// point that path is actually under, i.e. the filesystem path lives on.
+func findMountpoint(path string) (string, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer f.Close()
+
+	best := "/"
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+		if mountpoint := fields[4]; len(mountpoint) > len(best) && isUnderMountpoint(path, mountpoint) {
+			best = mountpoint
+		}
+	}
+	return best, errors.WithStack(scanner.Err())
+}
+
+// isUnderMountpoint reports whether path is mountpoint itself or a true
+// subpath of it. A bare strings.HasPrefix would also match a sibling like
+// /mnt/data2 against a mount at /mnt/data; requiring the boundary "/" (or
+// treating "/" itself as matching everything) avoids that.
+func isUnderMountpoint(path, mountpoint string) bool {
+	if mountpoint == "/" || path == mountpoint {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(mountpoint, "/")+"/")
+}
+
+func appendCgroupFile(path, line string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+	_, err = f.WriteString(line)
+	return errors.WithStack(err)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
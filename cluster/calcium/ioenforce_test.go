@@ -0,0 +1,48 @@
+package calcium
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+
+	"github.com/projecteru2/core/types"
+)
+
+func TestIOMaxLine(t *testing.T) {
+	vb := &types.VolumeBinding{ReadBytes: 1048576, WriteIOPS: 100}
+	assert.Equal(t, "253:0 rbps=1048576 wbps=max riops=max wiops=100", ioMaxLine(253, 0, vb))
+}
+
+func TestFindMountpointFallsBackToRoot(t *testing.T) {
+	mountpoint, err := findMountpoint("/definitely/not/a/real/path/xyz")
+	assert.NoError(t, err)
+	assert.Equal(t, "/", mountpoint)
+}
+
+func TestIsUnderMountpointRequiresPathBoundary(t *testing.T) {
+	// a mount at /mnt/data must not match the sibling directory
+	// /mnt/data2, even though it's a string prefix of it.
+	assert.False(t, isUnderMountpoint("/mnt/data2", "/mnt/data"))
+	assert.True(t, isUnderMountpoint("/mnt/data/sub", "/mnt/data"))
+	assert.True(t, isUnderMountpoint("/mnt/data", "/mnt/data"))
+	assert.True(t, isUnderMountpoint("/anything", "/"))
+}
+
+func TestBlockDeviceOfDecodesViaUnixMajorMinor(t *testing.T) {
+	var st syscall.Stat_t
+	assert.NoError(t, syscall.Stat("/", &st))
+	wantMaj, wantMin := unix.Major(uint64(st.Dev)), unix.Minor(uint64(st.Dev))
+
+	maj, min, err := blockDeviceOf("/")
+	assert.NoError(t, err)
+	assert.Equal(t, wantMaj, maj)
+	assert.Equal(t, wantMin, min)
+}
+
+func TestEnforceVolumeIOLimitsSkipsUnlimitedBindings(t *testing.T) {
+	c := NewTestCluster()
+	bindings := types.VolumeBindings{{Source: "/tmp", Destination: "/data"}}
+	assert.NoError(t, c.enforceVolumeIOLimits(t.TempDir(), bindings))
+}
@@ -0,0 +1,66 @@
+package calcium
+
+import (
+	"context"
+
+	enginetypes "github.com/projecteru2/core/engine/types"
+	"github.com/projecteru2/core/log"
+	"github.com/projecteru2/core/types"
+)
+
+// remapResource asks every resource plugin how a node's workloads should be
+// rebalanced across its current devices, merges their answers into a single
+// engine args map, and hands it to the node's engine to perform the actual
+// remap. The returned channel carries one message per workload the engine
+// touched.
+func (c *Calcium) remapResource(ctx context.Context, node *types.Node) (<-chan enginetypes.VirtualizationRemapMessage, error) {
+	workloads, err := c.store.ListNodeWorkloads(ctx, node.Name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	engineArgsMap := map[string]types.EngineArgs{}
+	for _, plugin := range c.resource.GetPlugins() {
+		for _, workload := range workloads {
+			resp, err := plugin.GetRemapArgs(ctx, node.Name, workload.ResourceArgs)
+			if err != nil {
+				return nil, err
+			}
+			for id, engineArgs := range resp.EngineArgsMap {
+				engineArgsMap[id] = engineArgs
+			}
+		}
+	}
+
+	return node.Engine.VirtualizationResourceRemap(ctx, engineArgsMap)
+}
+
+// doRemapResourceAndLog runs remapResource and logs its outcome, then
+// re-enforces volume IO cgroup limits for the node's workloads: a remap can
+// move a workload onto a different device, and the cgroup file backing its
+// old limits may no longer be the right one.
+func (c *Calcium) doRemapResourceAndLog(ctx context.Context, entry *log.Fields, node *types.Node) {
+	ch, err := c.remapResource(ctx, node)
+	if err != nil {
+		entry.Errorf(ctx, err, "failed to remap resources for node %s", node.Name)
+		return
+	}
+	for msg := range ch {
+		if msg.Error != nil {
+			entry.Errorf(ctx, msg.Error, "failed to remap workload %s", msg.ID)
+			continue
+		}
+		entry.Infof(ctx, "remapped workload %s", msg.ID)
+	}
+
+	workloads, err := c.store.ListNodeWorkloads(ctx, node.Name, nil)
+	if err != nil {
+		entry.Errorf(ctx, err, "failed to list workloads of node %s for IO enforcement", node.Name)
+		return
+	}
+	for _, workload := range workloads {
+		if err := c.enforceVolumeIOLimits(cgroupPathForWorkload(workload), workload.VolumeBindings); err != nil {
+			entry.Errorf(ctx, err, "failed to enforce volume IO limits for workload %s", workload.ID)
+		}
+	}
+}
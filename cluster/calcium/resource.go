@@ -0,0 +1,260 @@
+package calcium
+
+import (
+	"context"
+	"iter"
+
+	"github.com/projecteru2/core/log"
+	"github.com/projecteru2/core/resources"
+	"github.com/projecteru2/core/types"
+	"github.com/projecteru2/core/utils"
+
+	"github.com/pkg/errors"
+)
+
+// resourceStreamBuffer bounds how many node results PodResource can be
+// holding in flight before it blocks on the next node's validation, so a
+// slow gRPC client backpressures the etcd list instead of unbounded buffering.
+const resourceStreamBuffer = 16
+
+// ResourceStream lets a store yield a node's workloads one at a time -
+// e.g. a paginated etcd range scan - instead of collecting the whole
+// listing into a slice before anything can look at it. Stores that don't
+// implement it yet are wrapped by streamFromSlice around their batch
+// ListNodeWorkloads, which loses the memory benefit but keeps the same
+// calling convention.
+type ResourceStream interface {
+	StreamNodeWorkloads(ctx context.Context, nodename string) iter.Seq2[*types.Workload, error]
+}
+
+// streamFromSlice adapts a plain ListNodeWorkloads call to the iter.Seq2
+// shape, so callers don't need to special-case stores without ResourceStream.
+func streamFromSlice(workloads []*types.Workload, err error) iter.Seq2[*types.Workload, error] {
+	return func(yield func(*types.Workload, error) bool) {
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for _, workload := range workloads {
+			if !yield(workload, nil) {
+				return
+			}
+		}
+	}
+}
+
+// nodeWorkloadSeq returns a single-pass iterator over a node's current
+// workloads. It is safe to call more than once for the same node (each
+// call re-reads the store), which is how resourceInfoOfNode gives every
+// resource plugin its own pass without holding all workloads in memory at
+// once across plugins.
+func (c *Calcium) nodeWorkloadSeq(ctx context.Context, nodename string) iter.Seq2[*types.Workload, error] {
+	if stream, ok := c.store.(ResourceStream); ok {
+		return stream.StreamNodeWorkloads(ctx, nodename)
+	}
+	workloads, err := c.store.ListNodeWorkloads(ctx, nodename, nil)
+	return streamFromSlice(workloads, err)
+}
+
+// resourceArgsSeq re-exposes nodeWorkloadSeq as a stream of per-workload
+// resource args, for feeding directly to a resource plugin: the plugin
+// consumes one workload's args at a time and never sees the full list.
+// ctx cancellation stops the underlying stream - and any etcd reads behind
+// it - immediately instead of after the current node finishes draining.
+func (c *Calcium) resourceArgsSeq(ctx context.Context, nodename string, onErr *error) iter.Seq[map[string]types.WorkloadResourceArgs] {
+	return func(yield func(map[string]types.WorkloadResourceArgs) bool) {
+		for workload, err := range c.nodeWorkloadSeq(ctx, nodename) {
+			if ctx.Err() != nil {
+				*onErr = ctx.Err()
+				return
+			}
+			if err != nil {
+				*onErr = err
+				return
+			}
+			if !yield(workload.ResourceArgs) {
+				return
+			}
+		}
+	}
+}
+
+// snapshotNodeWorkloads captures a node's current workload list under its
+// distributed lock, then releases the lock as soon as the snapshot is
+// taken. Validation against the snapshot (engineValidateNode) happens
+// lock-free afterwards: ResourceValidate is a per-workload engine
+// round-trip, and holding the node lock across every one of them would
+// serialize unrelated scheduling on this node behind slow engine calls for
+// no reason - the lock only needs to cover reading the list itself.
+func (c *Calcium) snapshotNodeWorkloads(ctx context.Context, nodename string) (workloads []*types.Workload, err error) {
+	lock, err := c.store.CreateLock(nodename, c.config.Load().LockTimeout())
+	if err != nil {
+		return nil, err
+	}
+	if _, err = lock.Lock(ctx); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if unlockErr := lock.Unlock(ctx); unlockErr != nil {
+			log.WithFunc("calcium.snapshotNodeWorkloads").Errorf(ctx, unlockErr, "failed to unlock node %s", nodename)
+		}
+	}()
+
+	for workload, werr := range c.nodeWorkloadSeq(ctx, nodename) {
+		if ctx.Err() != nil {
+			return workloads, ctx.Err()
+		}
+		if werr != nil {
+			return workloads, werr
+		}
+		workloads = append(workloads, workload)
+	}
+	return workloads, nil
+}
+
+// engineValidateNode runs engine-level ResourceValidate and surfaces
+// per-workload drift on volume IO limits and storage class placement,
+// against a snapshot of the node's workloads taken under lock - but
+// validated lock-free, since none of it needs the lock once the snapshot
+// is in hand. desiredClasses tallies the snapshot's VolumeBinding storage
+// classes, for resourceInfoOfNode to diff against what each plugin confirms.
+func (c *Calcium) engineValidateNode(ctx context.Context, node *types.Node) (diffs []string, desiredClasses map[string]int64, err error) {
+	workloads, err := c.snapshotNodeWorkloads(ctx, node.Name)
+	desiredClasses = map[string]int64{}
+
+	for _, workload := range workloads {
+		if node.Engine != nil {
+			if verr := node.Engine.ResourceValidate(ctx, workload.ID, workload.Name, nil, nil); verr != nil {
+				diffs = append(diffs, workload.ID+" inspect failed: "+verr.Error())
+			}
+		}
+		diffs = append(diffs, c.volumeIODiffs(workload)...)
+		for _, vb := range workload.VolumeBindings {
+			if vb.StorageClass != "" {
+				desiredClasses[vb.StorageClass] += vb.SizeInBytes
+			}
+		}
+	}
+	return diffs, desiredClasses, err
+}
+
+// resourceInfoOfNode validates a node's current workloads and folds the
+// resulting diffs - engine, plugin, volume IO, and per-class storage
+// placement - into a single NodeResource. Only the workload snapshot taken
+// for engine validation is ever materialized in full; each plugin still
+// gets its own re-streamed pass and never sees the full list.
+func (c *Calcium) resourceInfoOfNode(ctx context.Context, node *types.Node, fix bool) (*types.NodeResource, error) {
+	nr := &types.NodeResource{Name: node.Name}
+
+	diffs, desiredClasses, err := c.engineValidateNode(ctx, node)
+	nr.Diffs = append(nr.Diffs, diffs...)
+	if err != nil {
+		return nr, err
+	}
+
+	for _, plugin := range c.resource.GetPlugins() {
+		var pluginErr error
+		seq := c.resourceArgsSeq(ctx, node.Name, &pluginErr)
+
+		var (
+			resp *resources.GetNodeResourceInfoResponse
+			err  error
+		)
+		if fix {
+			resp, err = plugin.FixNodeResource(ctx, node.Name, seq)
+		} else {
+			resp, err = plugin.GetNodeResourceInfo(ctx, node.Name, seq)
+		}
+		if pluginErr != nil {
+			nr.Diffs = append(nr.Diffs, pluginErr.Error())
+			continue
+		}
+		if err != nil {
+			nr.Diffs = append(nr.Diffs, err.Error())
+			continue
+		}
+		nr.Diffs = append(nr.Diffs, resp.Diffs...)
+		if resp.ResourceInfo != nil {
+			nr.ResourceInfo = append(nr.ResourceInfo, resp.ResourceInfo)
+			if len(desiredClasses) > 0 || len(resp.ResourceInfo.StorageClasses) > 0 {
+				nr.Diffs = append(nr.Diffs, resources.DiffStorageClasses(desiredClasses, resp.ResourceInfo.StorageClasses)...)
+			}
+		}
+	}
+
+	return nr, nil
+}
+
+// PodResource streams the resource usage of every node in a pod. Each
+// node's result is pushed to the returned channel as soon as it finishes
+// validating instead of waiting for the whole pod to be collected, and a
+// single node failing to list its workloads is reported as a diff on that
+// node rather than aborting the stream. ctx is checked both between nodes
+// and inside each node's own workload stream, so cancelling mid-node stops
+// further etcd reads immediately rather than after that node drains.
+func (c *Calcium) PodResource(ctx context.Context, podname string) (chan *types.NodeResource, error) {
+	nodes, err := c.store.GetNodesByPod(ctx, "", podname, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *types.NodeResource, resourceStreamBuffer)
+	utils.SentryGo(func() {
+		defer close(ch)
+		for _, n := range nodes {
+			if ctx.Err() != nil {
+				return
+			}
+
+			var nr *types.NodeResource
+			node, err := c.store.GetNode(ctx, n.Name)
+			if err != nil {
+				nr = &types.NodeResource{Name: n.Name, Diffs: []string{err.Error()}}
+			} else {
+				var infoErr error
+				nr, infoErr = c.resourceInfoOfNode(ctx, node, false)
+				if infoErr != nil {
+					if nr == nil {
+						nr = &types.NodeResource{Name: node.Name}
+					}
+					nr.Diffs = append(nr.Diffs, infoErr.Error())
+				}
+			}
+
+			select {
+			case ch <- nr:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	return ch, nil
+}
+
+// NodeResource returns the resource usage of a single node. If fix is true,
+// plugins are asked to reconcile their recorded usage before reporting it,
+// and the node as read by GetNode is written back with UpdateNodes; the
+// plugins' fixed ResourceInfo is returned in the NodeResource only and is
+// not copied onto the node.
+func (c *Calcium) NodeResource(ctx context.Context, nodename string, fix bool) (*types.NodeResource, error) {
+	if nodename == "" {
+		return nil, errors.WithStack(types.ErrEmptyNodeName)
+	}
+
+	node, err := c.store.GetNode(ctx, nodename)
+	if err != nil {
+		return nil, err
+	}
+
+	nr, err := c.resourceInfoOfNode(ctx, node, fix)
+	if err != nil {
+		return nil, err
+	}
+
+	if fix {
+		if err := c.store.UpdateNodes(ctx, node); err != nil {
+			return nil, err
+		}
+	}
+	return nr, nil
+}
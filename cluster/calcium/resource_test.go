@@ -3,7 +3,11 @@ package calcium
 import (
 	"context"
 	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	enginemocks "github.com/projecteru2/core/engine/mocks"
@@ -138,6 +142,40 @@ func TestNodeResource(t *testing.T) {
 	assert.Contains(t, details, "inspect failed")
 }
 
+func TestNodeResourceReportsStorageClassDrift(t *testing.T) {
+	c := NewTestCluster()
+	ctx := context.Background()
+	nodename := "testnode"
+	store := &storemocks.Store{}
+	c.store = store
+	plugin := c.resource.GetPlugins()[0].(*resourcemocks.Plugin)
+	// the plugin only confirms 5 bytes of "ssd" even though the workload
+	// below asks for 10: resourceInfoOfNode must surface that gap itself,
+	// DiffStorageClasses never gets called for us by the plugin.
+	plugin.On("GetNodeResourceInfo", mock.Anything, mock.Anything, mock.Anything).Return(&resources.GetNodeResourceInfoResponse{
+		ResourceInfo: &resources.NodeResourceInfo{StorageClasses: map[string]int64{"ssd": 5}},
+	}, nil)
+
+	lock := &lockmocks.DistributedLock{}
+	store.On("CreateLock", mock.Anything, mock.Anything).Return(lock, nil)
+	lock.On("Lock", mock.Anything).Return(context.TODO(), nil)
+	lock.On("Unlock", mock.Anything).Return(nil)
+
+	node := &types.Node{NodeMeta: types.NodeMeta{Name: nodename}}
+	store.On("GetNode", mock.Anything, nodename).Return(node, nil)
+	workload := &types.Workload{
+		ResourceArgs: map[string]types.WorkloadResourceArgs{},
+		VolumeBindings: types.VolumeBindings{
+			{Source: "AUTO", Destination: "/data", StorageClass: "ssd", SizeInBytes: 10},
+		},
+	}
+	store.On("ListNodeWorkloads", mock.Anything, mock.Anything, mock.Anything).Return([]*types.Workload{workload}, nil)
+
+	nr, err := c.NodeResource(ctx, nodename, false)
+	assert.NoError(t, err)
+	assert.Contains(t, strings.Join(nr.Diffs, ","), "storage class ssd: desired 10, confirmed 5")
+}
+
 func TestRemapResource(t *testing.T) {
 	c := NewTestCluster()
 	store := &storemocks.Store{}
@@ -153,8 +191,22 @@ func TestRemapResource(t *testing.T) {
 	engine := &enginemocks.API{}
 	node := &types.Node{Engine: engine}
 
+	// stub out device resolution and the cgroup directory so the io.max
+	// line enforceVolumeIOLimits writes can be asserted without touching
+	// a real block device or mountinfo.
+	cgroupDir := t.TempDir()
+	ioMaxPath := filepath.Join(cgroupDir, cgroupV2IOMax)
+	assert.NoError(t, os.WriteFile(ioMaxPath, nil, 0o644))
+	originalCgroupPath, originalResolve := cgroupPathForWorkload, resolveBlockDevice
+	defer func() { cgroupPathForWorkload, resolveBlockDevice = originalCgroupPath, originalResolve }()
+	cgroupPathForWorkload = func(*types.Workload) string { return cgroupDir }
+	resolveBlockDevice = func(string) (uint32, uint32, error) { return 253, 0, nil }
+
 	workload := &types.Workload{
 		ResourceArgs: map[string]types.WorkloadResourceArgs{},
+		VolumeBindings: types.VolumeBindings{
+			{Source: "/data", Destination: "/data", ReadBytes: 1048576},
+		},
 	}
 	store.On("ListNodeWorkloads", mock.Anything, mock.Anything, mock.Anything).Return([]*types.Workload{workload}, nil)
 	ch := make(chan enginetypes.VirtualizationRemapMessage, 1)
@@ -165,4 +217,73 @@ func TestRemapResource(t *testing.T) {
 	assert.Nil(t, err)
 
 	c.doRemapResourceAndLog(context.TODO(), log.WithField("test", "zc"), node)
+
+	written, err := os.ReadFile(ioMaxPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "253:0 rbps=1048576 wbps=max riops=max wiops=max", string(written))
+}
+
+// streamingStore wraps a plain store mock with a hand-rolled
+// StreamNodeWorkloads, so tests can drive a ResourceStream directly instead
+// of pre-materializing a []*types.Workload via ListNodeWorkloads.
+type streamingStore struct {
+	*storemocks.Store
+	stream func(yield func(*types.Workload, error) bool)
+}
+
+func (s *streamingStore) StreamNodeWorkloads(_ context.Context, _ string) iter.Seq2[*types.Workload, error] {
+	return s.stream
+}
+
+func TestPodResourceCancelMidStream(t *testing.T) {
+	c := NewTestCluster()
+	ctx, cancel := context.WithCancel(context.Background())
+	podname := "testpod"
+
+	var reads int32
+	store := &streamingStore{Store: &storemocks.Store{}}
+	c.store = store
+	plugin := c.resource.GetPlugins()[0].(*resourcemocks.Plugin)
+	plugin.On("GetNodeResourceInfo", mock.Anything, mock.Anything, mock.Anything).Return(&resources.GetNodeResourceInfoResponse{
+		ResourceInfo: &resources.NodeResourceInfo{},
+	}, nil)
+
+	lock := &lockmocks.DistributedLock{}
+	lock.On("Lock", mock.Anything).Return(context.TODO(), nil)
+	lock.On("Unlock", mock.Anything).Return(nil)
+	store.On("CreateLock", mock.Anything, mock.Anything).Return(lock, nil)
+
+	node := &types.Node{NodeMeta: types.NodeMeta{Name: "node1"}}
+	store.On("GetNodesByPod", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*types.Node{node}, nil)
+	store.On("GetNode", mock.Anything, "node1").Return(node, nil)
+
+	// simulates a paginated etcd range scan: a well-behaved store checks
+	// ctx between pages and stops fetching once it's cancelled, rather than
+	// handing back everything it could still read.
+	store.stream = func(yield func(*types.Workload, error) bool) {
+		for i := 0; i < 1000; i++ {
+			if ctx.Err() != nil {
+				return
+			}
+			atomic.AddInt32(&reads, 1)
+			if !yield(&types.Workload{ID: fmt.Sprintf("w%d", i), ResourceArgs: map[string]types.WorkloadResourceArgs{}}, nil) {
+				return
+			}
+			if i == 0 {
+				cancel()
+			}
+		}
+	}
+
+	ch, err := c.PodResource(ctx, podname)
+	assert.NoError(t, err)
+	for range ch {
+	}
+
+	// cancellation must stop the stream within a handful of workloads, not
+	// after the node's full (here, 1000-deep) listing drains:
+	// snapshotNodeWorkloads checks ctx after every workload it is handed.
+	// The snapshot still holds every workload read before the cancel, so
+	// without one a node's whole listing is held in memory.
+	assert.Less(t, int(atomic.LoadInt32(&reads)), 5)
 }
@@ -0,0 +1,50 @@
+package calcium
+
+import (
+	"context"
+
+	"github.com/projecteru2/core/serve/dockervolume"
+	"github.com/projecteru2/core/types"
+)
+
+// ScheduleVolume and ReleaseVolume make Calcium satisfy dockervolume.VolumeScheduler:
+// they thread an AUTO VolumeBinding through every resource plugin in turn,
+// the same set resourceInfoOfNode and remapResource already drive, so a
+// docker volume plugin request is scheduled with exactly the same
+// resource-plugin pipeline as a gRPC one.
+
+// ScheduleVolume asks each resource plugin in turn to allocate against
+// binding, feeding the previous plugin's result into the next - a plugin
+// that doesn't care about volumes at all can simply return the binding
+// unchanged.
+func (c *Calcium) ScheduleVolume(ctx context.Context, nodename string, binding *types.VolumeBinding) (*types.VolumeBinding, error) {
+	current := binding
+	for _, plugin := range c.resource.GetPlugins() {
+		scheduled, err := plugin.Alloc(ctx, nodename, current)
+		if err != nil {
+			return nil, err
+		}
+		if scheduled != nil {
+			current = scheduled
+		}
+	}
+	return current, nil
+}
+
+// ReleaseVolume calls Dealloc for binding on every resource plugin, whether
+// or not that plugin changed it in ScheduleVolume, stopping at the first error.
+func (c *Calcium) ReleaseVolume(ctx context.Context, nodename string, binding *types.VolumeBinding) error {
+	for _, plugin := range c.resource.GetPlugins() {
+		if err := plugin.Dealloc(ctx, nodename, binding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServeDockerVolumePlugin starts the Docker Volume Plugin protocol server
+// for nodename, scheduling AUTO volumes through c itself. It blocks until
+// ctx is done.
+func (c *Calcium) ServeDockerVolumePlugin(ctx context.Context, nodename string) error {
+	return dockervolume.NewServer(nodename, c).ListenAndServe(ctx)
+}
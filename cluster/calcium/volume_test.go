@@ -0,0 +1,35 @@
+package calcium
+
+import (
+	"context"
+	"testing"
+
+	resourcemocks "github.com/projecteru2/core/resources/mocks"
+	"github.com/projecteru2/core/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScheduleVolume(t *testing.T) {
+	c := NewTestCluster()
+	plugin := c.resource.GetPlugins()[0].(*resourcemocks.Plugin)
+
+	requested := &types.VolumeBinding{Source: "AUTO", Destination: "/data"}
+	scheduled := &types.VolumeBinding{Source: "/data0", Destination: "/data"}
+	plugin.On("Alloc", mock.Anything, "node1", requested).Return(scheduled, nil)
+
+	got, err := c.ScheduleVolume(context.Background(), "node1", requested)
+	assert.NoError(t, err)
+	assert.Same(t, scheduled, got)
+}
+
+func TestReleaseVolume(t *testing.T) {
+	c := NewTestCluster()
+	plugin := c.resource.GetPlugins()[0].(*resourcemocks.Plugin)
+
+	binding := &types.VolumeBinding{Source: "/data0", Destination: "/data"}
+	plugin.On("Dealloc", mock.Anything, "node1", binding).Return(nil)
+
+	assert.NoError(t, c.ReleaseVolume(context.Background(), "node1", binding))
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+// CheckCommand returns the `config check` CLI subcommand: it loads and
+// migrates the config at the given path without starting core, and prints
+// the resolved effective config so operators can sanity-check a deploy
+// before rolling it out.
+func CheckCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "check",
+		Usage: "validate a cluster config file and print the resolved effective config",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "config", Aliases: []string{"c"}, Value: "/etc/eru/core.yaml", Usage: "config file path"},
+		},
+		Action: func(c *cli.Context) error {
+			raw, err := os.ReadFile(c.String("config"))
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			cfg, moved, err := unmarshal(raw)
+			if err != nil {
+				return err
+			}
+			if len(moved) > 0 {
+				logMigration(context.Background(), moved)
+			}
+
+			out, err := yaml.Marshal(cfg)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			fmt.Println(string(out)) //nolint:forbidigo // CLI output
+			return nil
+		},
+	}
+}
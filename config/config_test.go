@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalMigratesLegacy(t *testing.T) {
+	raw := []byte(`
+bind: :5001
+zone: C1
+scheduler:
+  lock_ttl: 30
+`)
+	cfg, moved, err := unmarshal(raw)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, moved)
+	assert.Equal(t, currentVersion, cfg.Version)
+	assert.Equal(t, ":5001", cfg.Bind)
+	assert.Equal(t, "C1", cfg.Zone)
+	assert.Equal(t, 30, cfg.Scheduler.LockTTL)
+}
+
+func TestUnmarshalCurrentVersion(t *testing.T) {
+	raw := []byte(`
+version: 1
+bind: :5001
+`)
+	cfg, moved, err := unmarshal(raw)
+	assert.NoError(t, err)
+	assert.Empty(t, moved)
+	assert.Equal(t, ":5001", cfg.Bind)
+}
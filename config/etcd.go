@@ -0,0 +1,44 @@
+package config
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdSource reads the config document from a single etcd key and watches
+// it for changes, so a multi-node cluster can reload from one source of
+// truth instead of rolling out a file to every node.
+type EtcdSource struct {
+	Client *clientv3.Client
+	Key    string
+}
+
+// Load implements Source.
+func (e EtcdSource) Load(ctx context.Context) ([]byte, error) {
+	resp, err := e.Client.Get(ctx, e.Key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.Errorf("config: key %s not found", e.Key)
+	}
+	return resp.Kvs[0].Value, nil
+}
+
+// Watch implements Source.
+func (e EtcdSource) Watch(ctx context.Context) (<-chan struct{}, error) {
+	events := make(chan struct{}, 1)
+	watchCh := e.Client.Watch(ctx, e.Key)
+	go func() {
+		defer close(events)
+		for range watchCh {
+			select {
+			case events <- struct{}{}:
+			default: // a reload is already pending, coalesce
+			}
+		}
+	}()
+	return events, nil
+}
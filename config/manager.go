@@ -0,0 +1,185 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/core/log"
+)
+
+// Source loads the raw config document and notifies on changes. FileSource
+// and EtcdSource are the two implementations components wire up; tests can
+// supply their own.
+type Source interface {
+	// Load returns the current raw document.
+	Load(ctx context.Context) ([]byte, error)
+	// Watch fires whenever the underlying document changes. It is closed
+	// when ctx is done.
+	Watch(ctx context.Context) (<-chan struct{}, error)
+}
+
+// OnConfigChange is registered by components (calcium, resource plugins,
+// the gRPC bind) that need to react to a config reload without a restart.
+type OnConfigChange func(old, new *Config)
+
+// Manager owns the single source of truth for the cluster config: it loads
+// the document once at startup, then keeps it current via the source's
+// Watch channel and fans out diffs to subscribers.
+type Manager struct {
+	source Source
+
+	mu   sync.RWMutex
+	cfg  *Config
+	subs []OnConfigChange
+}
+
+// NewManager loads the initial config from source, migrating it from the
+// deprecated flat YAML if necessary.
+func NewManager(ctx context.Context, source Source) (*Manager, error) {
+	m := &Manager{source: source}
+	if err := m.reload(ctx); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Get returns the current effective config. Callers must not mutate it.
+func (m *Manager) Get() *Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg
+}
+
+// OnChange registers a handler invoked after every successful reload.
+// Handlers run synchronously on the reload goroutine, in registration order.
+func (m *Manager) OnChange(fn OnConfigChange) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subs = append(m.subs, fn)
+}
+
+// Run watches the source for changes until ctx is done, reloading and
+// notifying subscribers on every event. Reload errors are logged and do
+// not stop the watch loop: a bad write to the config file shouldn't wedge
+// already-running components onto a half-applied config.
+func (m *Manager) Run(ctx context.Context) error {
+	events, err := m.source.Watch(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := m.reload(ctx); err != nil {
+				log.Errorf(ctx, err, "[config] reload failed, keeping previous config")
+			}
+		}
+	}
+}
+
+func (m *Manager) reload(ctx context.Context) error {
+	raw, err := m.source.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg, moved, err := unmarshal(raw)
+	if err != nil {
+		return err
+	}
+	if len(moved) > 0 {
+		logMigration(ctx, moved)
+	}
+
+	m.mu.Lock()
+	old := m.cfg
+	m.cfg = cfg
+	subs := append([]OnConfigChange(nil), m.subs...)
+	m.mu.Unlock()
+
+	for _, sub := range subs {
+		sub(old, cfg)
+	}
+	return nil
+}
+
+// FileSource reads the config document from a path on disk and watches it
+// with fsnotify, so local deployments get hot reload without etcd.
+type FileSource struct {
+	Path string
+}
+
+// Load implements Source.
+func (f FileSource) Load(context.Context) ([]byte, error) {
+	raw, err := os.ReadFile(f.Path)
+	return raw, errors.WithStack(err)
+}
+
+// Watch implements Source.
+//
+// It watches the parent directory rather than f.Path directly: editors and
+// config management tools commonly "save" by writing a temp file and
+// renaming it over the target, which replaces the inode fsnotify would
+// otherwise be watching and silently stops delivering further events. The
+// directory watch survives that, so a subsequent atomic save is still
+// picked up; events are filtered down to f.Path by basename. A Remove/Rename
+// of the file itself also re-adds the directory, which is a no-op while the
+// directory is still watched.
+func (f FileSource) Watch(ctx context.Context) (<-chan struct{}, error) {
+	dir := filepath.Dir(f.Path)
+	name := filepath.Base(f.Path)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := watcher.Add(dir); err != nil {
+		_ = watcher.Close()
+		return nil, errors.WithStack(err)
+	}
+
+	events := make(chan struct{}, 1)
+	go func() {
+		defer watcher.Close()
+		defer close(events)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Base(ev.Name) != name {
+					continue
+				}
+				if ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+					// the file's inode is gone; the directory watch
+					// still sees the replacement, re-adding it is a no-op.
+					_ = watcher.Add(dir)
+				}
+				if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				select {
+				case events <- struct{}{}:
+				default: // a reload is already pending, coalesce
+				}
+			case <-watcher.Errors:
+				continue
+			}
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSourceWatchSurvivesAtomicSave(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "core.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("bind: :1\n"), 0o644))
+
+	src := FileSource{Path: path}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := src.Watch(ctx)
+	assert.NoError(t, err)
+
+	// an editor's atomic save writes a temp file, then renames it over the
+	// target, replacing its inode out from under a naive file watch.
+	tmp := filepath.Join(dir, ".core.yaml.tmp")
+	assert.NoError(t, os.WriteFile(tmp, []byte("bind: :2\n"), 0o644))
+	assert.NoError(t, os.Rename(tmp, path))
+
+	select {
+	case <-events:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a reload event after the first atomic save")
+	}
+
+	// a second atomic save after the rename must still be observed - this
+	// is what watching the directory rather than the file's inode ensures.
+	tmp2 := filepath.Join(dir, ".core.yaml.tmp2")
+	assert.NoError(t, os.WriteFile(tmp2, []byte("bind: :3\n"), 0o644))
+	assert.NoError(t, os.Rename(tmp2, path))
+
+	select {
+	case <-events:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a reload event after the second atomic save")
+	}
+}
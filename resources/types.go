@@ -0,0 +1,81 @@
+// Package resources defines the resource plugin interface calcium drives
+// to validate, allocate and report on node resources (cpumem, volume, ...).
+package resources
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sort"
+
+	"github.com/projecteru2/core/types"
+)
+
+// Plugin is implemented by every resource plugin (cpumem, volume, ...) and
+// driven by calcium over the course of scheduling, validation and remap.
+type Plugin interface {
+	// GetNodeResourceInfo returns the resource usage and diffs (if any) of
+	// a node. workloadResourceArgs is consumed once, in order, so callers
+	// can feed it from a stream without materializing every workload's
+	// args up front.
+	GetNodeResourceInfo(ctx context.Context, nodename string, workloadResourceArgs iter.Seq[map[string]types.WorkloadResourceArgs]) (*GetNodeResourceInfoResponse, error)
+	// FixNodeResource reconciles a node's recorded resource usage against
+	// reality and returns the corrected info. Same streaming contract as
+	// GetNodeResourceInfo.
+	FixNodeResource(ctx context.Context, nodename string, workloadResourceArgs iter.Seq[map[string]types.WorkloadResourceArgs]) (*GetNodeResourceInfoResponse, error)
+	// GetRemapArgs returns the per-workload engine args needed to rebalance
+	// resources pinned to workloads still running on a node.
+	GetRemapArgs(ctx context.Context, nodename string, workloadResourceArgs map[string]types.WorkloadResourceArgs) (*GetRemapArgsResponse, error)
+	// Alloc schedules a brand new resource request - e.g. an AUTO volume
+	// binding with no Source yet - onto nodename and returns it with its
+	// Source resolved to a concrete host identifier.
+	Alloc(ctx context.Context, nodename string, request *types.VolumeBinding) (*types.VolumeBinding, error)
+	// Dealloc releases a binding previously returned by Alloc.
+	Dealloc(ctx context.Context, nodename string, binding *types.VolumeBinding) error
+}
+
+// NodeResourceInfo describes a node's resource capacity and usage as seen
+// by a single resource plugin.
+type NodeResourceInfo struct {
+	Capacity map[string]interface{} `json:"capacity"`
+	Usage    map[string]interface{} `json:"usage"`
+	// StorageClasses tallies, for volume plugins that partition storage by
+	// class (ssd, nvme, ...), the bytes the plugin confirms as placed in
+	// each class, keyed by class name; calcium diffs it against the
+	// classes the node's VolumeBindings ask for.
+	StorageClasses map[string]int64 `json:"storage_classes,omitempty"`
+}
+
+// GetNodeResourceInfoResponse is returned by GetNodeResourceInfo and
+// FixNodeResource.
+type GetNodeResourceInfoResponse struct {
+	ResourceInfo *NodeResourceInfo
+	Diffs        []string
+}
+
+// GetRemapArgsResponse is returned by GetRemapArgs.
+type GetRemapArgsResponse struct {
+	EngineArgsMap map[string]types.EngineArgs
+}
+
+// DiffStorageClasses compares the storage classes a placement actually
+// confirmed against what was desired (e.g. from a VolumeBinding's
+// StorageClass) and returns one human-readable line per class that is
+// missing, short, or wasn't asked for - so fragmentation across a pod is
+// visible instead of only the aggregate capacity number.
+func DiffStorageClasses(desired, confirmed map[string]int64) []string {
+	var diffs []string
+	for class, want := range desired {
+		got := confirmed[class]
+		if got < want {
+			diffs = append(diffs, fmt.Sprintf("storage class %s: desired %d, confirmed %d", class, want, got))
+		}
+	}
+	for class, got := range confirmed {
+		if _, wanted := desired[class]; !wanted && got > 0 {
+			diffs = append(diffs, fmt.Sprintf("storage class %s: confirmed %d but not requested", class, got))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
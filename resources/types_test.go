@@ -0,0 +1,17 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffStorageClasses(t *testing.T) {
+	desired := map[string]int64{"ssd": 10, "nvme": 5}
+	confirmed := map[string]int64{"ssd": 10, "hdd": 3}
+
+	diffs := DiffStorageClasses(desired, confirmed)
+	assert.Contains(t, diffs, "storage class nvme: desired 5, confirmed 0")
+	assert.Contains(t, diffs, "storage class hdd: confirmed 3 but not requested")
+	assert.NotContains(t, diffs, "storage class ssd: desired 10, confirmed 10")
+}
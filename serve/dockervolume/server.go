@@ -0,0 +1,235 @@
+// Package dockervolume implements the Docker Volume Plugin protocol on top
+// of eru's AUTO volume scheduling, so `docker volume create -d eru` can be
+// used directly on any node without going through the core gRPC API.
+package dockervolume
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/core/log"
+	"github.com/projecteru2/core/types"
+)
+
+const (
+	// defaultSocketPath is where the docker daemon looks for plugin sockets.
+	defaultSocketPath = "/run/docker/plugins/eru.sock"
+	// defaultSpecPath registers the plugin so `-d eru` resolves to the socket above.
+	defaultSpecPath = "/etc/docker/plugins/eru.spec"
+	driverName      = "eru"
+)
+
+// VolumeScheduler is the subset of calcium this server needs: turning an
+// AUTO VolumeBinding into a concrete host path and releasing it again.
+type VolumeScheduler interface {
+	ScheduleVolume(ctx context.Context, nodename string, binding *types.VolumeBinding) (*types.VolumeBinding, error)
+	ReleaseVolume(ctx context.Context, nodename string, binding *types.VolumeBinding) error
+}
+
+// Server serves the Docker Volume Plugin protocol over a UNIX socket.
+type Server struct {
+	nodename   string
+	socketPath string
+	specPath   string
+	scheduler  VolumeScheduler
+
+	mu       sync.Mutex
+	bindings map[string]*types.VolumeBinding // volume name -> scheduled binding
+}
+
+// NewServer returns a Server bound to the given node, ready to ListenAndServe.
+func NewServer(nodename string, scheduler VolumeScheduler) *Server {
+	return &Server{
+		nodename:   nodename,
+		socketPath: defaultSocketPath,
+		specPath:   defaultSpecPath,
+		scheduler:  scheduler,
+		bindings:   map[string]*types.VolumeBinding{},
+	}
+}
+
+// ListenAndServe listens on the plugin UNIX socket, writes the plugin spec
+// file so the docker daemon can discover it, and serves until ctx is done.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	if err := os.MkdirAll(filepath.Dir(s.socketPath), 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	_ = os.Remove(s.socketPath)
+
+	listener, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := s.writeSpec(); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Plugin.Activate", s.handleActivate)
+	mux.HandleFunc("/VolumeDriver.Create", s.handleCreate)
+	mux.HandleFunc("/VolumeDriver.Get", s.handleGet)
+	mux.HandleFunc("/VolumeDriver.List", s.handleList)
+	mux.HandleFunc("/VolumeDriver.Remove", s.handleRemove)
+	mux.HandleFunc("/VolumeDriver.Path", s.handlePath)
+	mux.HandleFunc("/VolumeDriver.Mount", s.handleMount)
+	mux.HandleFunc("/VolumeDriver.Unmount", s.handleUnmount)
+	mux.HandleFunc("/VolumeDriver.Capabilities", s.handleCapabilities)
+
+	srv := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+
+	log.Infof(ctx, "[dockervolume] serving on %s", s.socketPath)
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// writeSpec emits the plugin spec file docker reads to resolve `-d eru`
+// to this server's UNIX socket.
+func (s *Server) writeSpec() error {
+	if err := os.MkdirAll(filepath.Dir(s.specPath), 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.WriteFile(s.specPath, []byte("unix://"+s.socketPath+"\n"), 0o644))
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", mediaType)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, errorResponse{Err: err.Error()})
+}
+
+func (s *Server) handleActivate(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, pluginSpec{Implements: []string{"VolumeDriver"}})
+}
+
+func (s *Server) handleCapabilities(w http.ResponseWriter, _ *http.Request) {
+	var resp capabilitiesResponse
+	resp.Capabilities.Scope = "global"
+	writeJSON(w, resp)
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, errors.WithStack(err))
+		return
+	}
+
+	binding, err := bindingFromOpts(req.Name, req.Opts)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	scheduled, err := s.scheduler.ScheduleVolume(r.Context(), s.nodename, binding)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.bindings[req.Name] = scheduled
+	s.mu.Unlock()
+
+	writeJSON(w, errorResponse{})
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	var req nameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, getResponse{Err: err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	binding, ok := s.bindings[req.Name]
+	s.mu.Unlock()
+	if !ok {
+		writeJSON(w, getResponse{Err: "no such volume: " + req.Name})
+		return
+	}
+
+	writeJSON(w, getResponse{Volume: &volumeInfo{Name: req.Name, Mountpoint: binding.Source}})
+}
+
+func (s *Server) handleList(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	volumes := make([]*volumeInfo, 0, len(s.bindings))
+	for name, binding := range s.bindings {
+		volumes = append(volumes, &volumeInfo{Name: name, Mountpoint: binding.Source})
+	}
+	writeJSON(w, listResponse{Volumes: volumes})
+}
+
+func (s *Server) handleRemove(w http.ResponseWriter, r *http.Request) {
+	var req nameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, errors.WithStack(err))
+		return
+	}
+
+	s.mu.Lock()
+	binding, ok := s.bindings[req.Name]
+	delete(s.bindings, req.Name)
+	s.mu.Unlock()
+	if !ok {
+		writeJSON(w, errorResponse{})
+		return
+	}
+
+	if err := s.scheduler.ReleaseVolume(r.Context(), s.nodename, binding); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, errorResponse{})
+}
+
+func (s *Server) handlePath(w http.ResponseWriter, r *http.Request) {
+	s.resolveMountpoint(w, r)
+}
+
+func (s *Server) handleMount(w http.ResponseWriter, r *http.Request) {
+	s.resolveMountpoint(w, r)
+}
+
+func (s *Server) handleUnmount(w http.ResponseWriter, _ *http.Request) {
+	// AUTO bindings are released on Remove, not Unmount: several containers
+	// may share the same volume. Unmount is a no-op; mount IDs are not tracked.
+	writeJSON(w, errorResponse{})
+}
+
+func (s *Server) resolveMountpoint(w http.ResponseWriter, r *http.Request) {
+	var req mountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, pathResponse{Err: err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	binding, ok := s.bindings[req.Name]
+	s.mu.Unlock()
+	if !ok {
+		writeJSON(w, pathResponse{Err: "no such volume: " + req.Name})
+		return
+	}
+
+	writeJSON(w, pathResponse{Mountpoint: binding.Source})
+}
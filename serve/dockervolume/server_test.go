@@ -0,0 +1,132 @@
+package dockervolume
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/core/types"
+)
+
+// fakeScheduler is a minimal in-memory VolumeScheduler for exercising the
+// HTTP handlers without a real Calcium/resource plugin behind them.
+type fakeScheduler struct {
+	scheduleErr error
+	releaseErr  error
+	released    []*types.VolumeBinding
+}
+
+func (f *fakeScheduler) ScheduleVolume(_ context.Context, _ string, binding *types.VolumeBinding) (*types.VolumeBinding, error) {
+	if f.scheduleErr != nil {
+		return nil, f.scheduleErr
+	}
+	scheduled := *binding
+	scheduled.Source = "/var/lib/eru/volumes/" + binding.Destination
+	return &scheduled, nil
+}
+
+func (f *fakeScheduler) ReleaseVolume(_ context.Context, _ string, binding *types.VolumeBinding) error {
+	f.released = append(f.released, binding)
+	return f.releaseErr
+}
+
+func newTestServer(scheduler VolumeScheduler) *Server {
+	return NewServer("test-node", scheduler)
+}
+
+func doRequest(t *testing.T, handler http.HandlerFunc, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	raw, err := json.Marshal(body)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(raw))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestHandleCreate(t *testing.T) {
+	scheduler := &fakeScheduler{}
+	s := newTestServer(scheduler)
+
+	rec := doRequest(t, s.handleCreate, createRequest{Name: "vol1", Opts: map[string]string{"size": "1G"}})
+
+	var resp errorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Err)
+
+	s.mu.Lock()
+	binding, ok := s.bindings["vol1"]
+	s.mu.Unlock()
+	assert.True(t, ok)
+	assert.Equal(t, "/var/lib/eru/volumes//vol1", binding.Source)
+}
+
+func TestHandleCreateScheduleFailure(t *testing.T) {
+	scheduler := &fakeScheduler{scheduleErr: assert.AnError}
+	s := newTestServer(scheduler)
+
+	rec := doRequest(t, s.handleCreate, createRequest{Name: "vol1"})
+
+	var resp errorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.Err)
+}
+
+func TestHandleMountResolvesScheduledMountpoint(t *testing.T) {
+	scheduler := &fakeScheduler{}
+	s := newTestServer(scheduler)
+	doRequest(t, s.handleCreate, createRequest{Name: "vol1"})
+
+	rec := doRequest(t, s.handleMount, mountRequest{Name: "vol1", ID: "mount-1"})
+
+	var resp pathResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Err)
+	assert.NotEmpty(t, resp.Mountpoint)
+}
+
+func TestHandleMountUnknownVolume(t *testing.T) {
+	s := newTestServer(&fakeScheduler{})
+
+	rec := doRequest(t, s.handleMount, mountRequest{Name: "nope", ID: "mount-1"})
+
+	var resp pathResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.Err)
+}
+
+func TestHandleRemoveReleasesVolume(t *testing.T) {
+	scheduler := &fakeScheduler{}
+	s := newTestServer(scheduler)
+	doRequest(t, s.handleCreate, createRequest{Name: "vol1"})
+
+	rec := doRequest(t, s.handleRemove, nameRequest{Name: "vol1"})
+
+	var resp errorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Err)
+	assert.Len(t, scheduler.released, 1)
+
+	s.mu.Lock()
+	_, ok := s.bindings["vol1"]
+	s.mu.Unlock()
+	assert.False(t, ok)
+}
+
+func TestHandleRemoveUnknownVolumeIsNoop(t *testing.T) {
+	scheduler := &fakeScheduler{}
+	s := newTestServer(scheduler)
+
+	rec := doRequest(t, s.handleRemove, nameRequest{Name: "nope"})
+
+	var resp errorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Err)
+	assert.Empty(t, scheduler.released)
+}
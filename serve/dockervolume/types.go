@@ -0,0 +1,67 @@
+package dockervolume
+
+// mediaType is the content type required by the Docker Volume Plugin protocol.
+const mediaType = "application/vnd.docker.plugins.v1.1+json"
+
+// pluginSpec answers /Plugin.Activate, naming the plugin subsystems this
+// server implements. The spec file written on startup is a separate plain
+// unix:// socket address (see Server.writeSpec).
+type pluginSpec struct {
+	Implements []string `json:"Implements"`
+}
+
+// createRequest is the payload for POST /VolumeDriver.Create.
+type createRequest struct {
+	Name string            `json:"Name"`
+	Opts map[string]string `json:"Opts"`
+}
+
+// nameRequest is the payload of /VolumeDriver.Get and /VolumeDriver.Remove,
+// which only carry the volume name.
+type nameRequest struct {
+	Name string `json:"Name"`
+}
+
+// mountRequest additionally carries the ID of the mount operation so a
+// single volume can be mounted by several containers concurrently.
+type mountRequest struct {
+	Name string `json:"Name"`
+	ID   string `json:"ID"`
+}
+
+// volumeInfo is returned in Get/List responses.
+type volumeInfo struct {
+	Name       string            `json:"Name"`
+	Mountpoint string            `json:"Mountpoint,omitempty"`
+	Status     map[string]string `json:"Status,omitempty"`
+}
+
+// errorResponse is the shape every endpoint falls back to on failure.
+type errorResponse struct {
+	Err string `json:"Err"`
+}
+
+// getResponse answers /VolumeDriver.Get.
+type getResponse struct {
+	Volume *volumeInfo `json:"Volume,omitempty"`
+	Err    string      `json:"Err"`
+}
+
+// listResponse answers /VolumeDriver.List.
+type listResponse struct {
+	Volumes []*volumeInfo `json:"Volumes"`
+	Err     string        `json:"Err"`
+}
+
+// pathResponse / mountResponse share the mountpoint shape.
+type pathResponse struct {
+	Mountpoint string `json:"Mountpoint"`
+	Err        string `json:"Err"`
+}
+
+// capabilitiesResponse answers /VolumeDriver.Capabilities.
+type capabilitiesResponse struct {
+	Capabilities struct {
+		Scope string `json:"Scope"`
+	} `json:"Capabilities"`
+}
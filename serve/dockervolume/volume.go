@@ -0,0 +1,99 @@
+package dockervolume
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/core/types"
+)
+
+// sizeUnits maps the suffix accepted in the `size` opt to its byte multiplier,
+// so `docker volume create -d eru -o size=10G` reads the same as the
+// -o size=10737418240 the raw VolumeBinding format expects.
+var sizeUnits = map[string]int64{
+	"":  1,
+	"k": 1 << 10,
+	"m": 1 << 20,
+	"g": 1 << 30,
+	"t": 1 << 40,
+}
+
+// parseSize turns a human size like "10G" into bytes.
+func parseSize(raw string) (int64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	unit := ""
+	if last := raw[len(raw)-1:]; strconv.IsPrint(rune(last[0])) && !('0' <= last[0] && last[0] <= '9') {
+		unit = strings.ToLower(last)
+		raw = raw[:len(raw)-1]
+	}
+	mul, ok := sizeUnits[unit]
+	if !ok {
+		return 0, errors.WithStack(fmt.Errorf("invalid size unit: %s", unit))
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return n * mul, nil
+}
+
+// bindingFromOpts translates the Opts map of a /VolumeDriver.Create request
+// into an AUTO VolumeBinding, reusing the same 8-field raw format that
+// types.NewVolumeBinding already parses for the gRPC API.
+//
+// name becomes the synthetic destination: the docker volume plugin protocol
+// has no notion of an in-container mount path at create time, so we key the
+// binding on the volume name. The real host path is chosen when Create
+// schedules the binding, and Mount returns it.
+func bindingFromOpts(name string, opts map[string]string) (*types.VolumeBinding, error) {
+	size, err := parseSize(opts["size"])
+	if err != nil {
+		return nil, err
+	}
+
+	flags := ""
+	if monopoly, _ := strconv.ParseBool(opts["m"]); monopoly {
+		flags = "m"
+	}
+
+	riops, err := parseIOOpt(opts, "riops")
+	if err != nil {
+		return nil, err
+	}
+	wiops, err := parseIOOpt(opts, "wiops")
+	if err != nil {
+		return nil, err
+	}
+	rbytes, err := parseIOOpt(opts, "rbytes")
+	if err != nil {
+		return nil, err
+	}
+	wbytes, err := parseIOOpt(opts, "wbytes")
+	if err != nil {
+		return nil, err
+	}
+
+	raw := fmt.Sprintf("AUTO:/%s:%s:%d:%d:%d:%d:%d", name, flags, size, riops, wiops, rbytes, wbytes)
+	if class := opts["class"]; class != "" {
+		raw = fmt.Sprintf("%s:class=%s", raw, class)
+	}
+	return types.NewVolumeBinding(raw)
+}
+
+func parseIOOpt(opts map[string]string, key string) (int64, error) {
+	raw, ok := opts[key]
+	if !ok || raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, errors.WithStack(fmt.Errorf("invalid %s: %w", key, err))
+	}
+	return v, nil
+}
@@ -0,0 +1,42 @@
+package dockervolume
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSize(t *testing.T) {
+	n, err := parseSize("10G")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10<<30), n)
+
+	n, err = parseSize("")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	_, err = parseSize("10X")
+	assert.Error(t, err)
+}
+
+func TestBindingFromOpts(t *testing.T) {
+	vb, err := bindingFromOpts("data", map[string]string{
+		"size":  "1G",
+		"m":     "true",
+		"riops": "100",
+		"wiops": "200",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1<<30), vb.SizeInBytes)
+	assert.Equal(t, "m", vb.Flags)
+	assert.Equal(t, int64(100), vb.ReadIOPS)
+	assert.Equal(t, int64(200), vb.WriteIOPS)
+	assert.True(t, vb.RequireSchedule())
+	assert.True(t, vb.RequireScheduleMonopoly())
+}
+
+func TestBindingFromOptsStorageClass(t *testing.T) {
+	vb, err := bindingFromOpts("data", map[string]string{"class": "ssd"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ssd", vb.StorageClass)
+}
@@ -13,16 +13,17 @@ import (
 
 const auto = "AUTO"
 
-// VolumeBinding src:dst[:flags][:size][:read_iops:write_iops:read_bytes:write_bytes]
+// VolumeBinding src:dst[:flags][:size][:read_iops:write_iops:read_bytes:write_bytes][:class=xxx]
 type VolumeBinding struct {
-	Source      string
-	Destination string
-	Flags       string
-	SizeInBytes int64
-	ReadIOPS    int64
-	WriteIOPS   int64
-	ReadBytes   int64
-	WriteBytes  int64
+	Source       string
+	Destination  string
+	Flags        string
+	SizeInBytes  int64
+	ReadIOPS     int64
+	WriteIOPS    int64
+	ReadBytes    int64
+	WriteBytes   int64
+	StorageClass string
 }
 
 // NewVolumeBinding returns pointer of VolumeBinding
@@ -30,6 +31,13 @@ func NewVolumeBinding(rawVolume string) (_ *VolumeBinding, err error) {
 	vb := &VolumeBinding{}
 
 	parts := strings.Split(rawVolume, ":")
+	if n := len(parts); n > 0 {
+		if value, ok := strings.CutPrefix(parts[n-1], "class="); ok {
+			vb.StorageClass = value
+			parts = parts[:n-1]
+		}
+	}
+
 	switch len(parts) {
 	case 8:
 		if vb.ReadIOPS, err = strconv.ParseInt(parts[4], 10, 64); err != nil {
@@ -121,6 +129,9 @@ func (vb VolumeBinding) ToString(normalize bool) (volume string) {
 	default:
 		volume = fmt.Sprintf("%s:%s:%s:%d", vb.Source, vb.Destination, flags, vb.SizeInBytes)
 	}
+	if vb.StorageClass != "" {
+		volume = fmt.Sprintf("%s:class=%s", volume, vb.StorageClass)
+	}
 	return volume
 }
 
@@ -210,10 +221,10 @@ func (vbs VolumeBindings) TotalSize() (total int64) {
 
 // MergeVolumeBindings combines two VolumeBindings
 func MergeVolumeBindings(vbs1 VolumeBindings, vbs2 ...VolumeBindings) (vbs VolumeBindings) {
-	sizeMap := make(map[[3]string][]int64) // {["AUTO", "/data", "rw"]: [100, 0, 0, 0, 0]}
+	sizeMap := make(map[[4]string][]int64) // {["AUTO", "/data", "rw", "ssd"]: [100, 0, 0, 0, 0]}
 	for _, vbs := range append(vbs2, vbs1) {
 		for _, vb := range vbs {
-			key := [3]string{vb.Source, vb.Destination, vb.Flags}
+			key := [4]string{vb.Source, vb.Destination, vb.Flags, vb.StorageClass}
 			if _, ok := sizeMap[key]; !ok || sizeMap[key] == nil {
 				sizeMap[key] = []int64{vb.SizeInBytes, vb.ReadIOPS, vb.WriteIOPS, vb.ReadBytes, vb.WriteBytes}
 			} else {
@@ -231,14 +242,15 @@ func MergeVolumeBindings(vbs1 VolumeBindings, vbs2 ...VolumeBindings) (vbs Volum
 			continue
 		}
 		vbs = append(vbs, &VolumeBinding{
-			Source:      key[0],
-			Destination: key[1],
-			Flags:       key[2],
-			SizeInBytes: para[0],
-			ReadIOPS:    para[1],
-			WriteIOPS:   para[2],
-			ReadBytes:   para[3],
-			WriteBytes:  para[4],
+			Source:       key[0],
+			Destination:  key[1],
+			Flags:        key[2],
+			StorageClass: key[3],
+			SizeInBytes:  para[0],
+			ReadIOPS:     para[1],
+			WriteIOPS:    para[2],
+			ReadBytes:    para[3],
+			WriteBytes:   para[4],
 		})
 	}
 	return
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeBindingStorageClass(t *testing.T) {
+	vb, err := NewVolumeBinding("AUTO:/data:rw:1024:class=ssd")
+	assert.NoError(t, err)
+	assert.Equal(t, "ssd", vb.StorageClass)
+	assert.Equal(t, int64(1024), vb.SizeInBytes)
+	assert.Equal(t, "AUTO:/data:rw:1024:class=ssd", vb.ToString(false))
+
+	vb, err = NewVolumeBinding("AUTO:/data:class=nvme")
+	assert.NoError(t, err)
+	assert.Equal(t, "nvme", vb.StorageClass)
+	assert.Equal(t, "", vb.Flags)
+	assert.Equal(t, "AUTO:/data:class=nvme", vb.ToString(false))
+}
+
+func TestVolumeBindingDestinationWithEqualsSign(t *testing.T) {
+	// a positional field containing "=" (e.g. an odd destination path)
+	// must not be mistaken for a "class=" suffix.
+	vb, err := NewVolumeBinding("/src:/dst=x")
+	assert.NoError(t, err)
+	assert.Equal(t, "/dst=x", vb.Destination)
+	assert.Equal(t, "", vb.StorageClass)
+}
+
+func TestMergeVolumeBindingsByStorageClass(t *testing.T) {
+	vbs1 := VolumeBindings{{Source: "AUTO", Destination: "/data", StorageClass: "ssd", SizeInBytes: 100}}
+	vbs2 := VolumeBindings{{Source: "AUTO", Destination: "/data", StorageClass: "nvme", SizeInBytes: 200}}
+
+	merged := MergeVolumeBindings(vbs1, vbs2)
+	assert.Len(t, merged, 2)
+
+	classes := map[string]int64{}
+	for _, vb := range merged {
+		classes[vb.StorageClass] = vb.SizeInBytes
+	}
+	assert.Equal(t, int64(100), classes["ssd"])
+	assert.Equal(t, int64(200), classes["nvme"])
+}